@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonArrayWriter streams a JSON array to w one element at a time, holding
+// only one marshaled element in memory at once. This keeps memory bounded
+// when scanning libraries of thousands of books, and lets the array
+// written so far survive a crash partway through.
+type jsonArrayWriter struct {
+	w          io.Writer
+	pretty     bool
+	wroteFirst bool
+}
+
+func newJSONArrayWriter(w io.Writer, pretty bool) *jsonArrayWriter {
+	fmt.Fprint(w, "[")
+	return &jsonArrayWriter{w: w, pretty: pretty}
+}
+
+// Write appends v to the array.
+func (j *jsonArrayWriter) Write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if j.wroteFirst {
+		fmt.Fprint(j.w, ",")
+	}
+	j.wroteFirst = true
+
+	if j.pretty {
+		fmt.Fprint(j.w, "\n  ")
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "  ", "  "); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+
+	_, err = j.w.Write(data)
+	return err
+}
+
+// Close finishes the array. It must be called exactly once.
+func (j *jsonArrayWriter) Close() error {
+	if j.pretty && j.wroteFirst {
+		fmt.Fprint(j.w, "\n")
+	}
+	_, err := fmt.Fprint(j.w, "]\n")
+	return err
+}
@@ -0,0 +1,239 @@
+// Command epub-metadata-extractor walks a directory of EPUB files and emits
+// their metadata as a JSON array.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fazalmajid/epub/pkg/epub"
+)
+
+// bookMetadata is epub.Metadata plus the filesystem details that only make
+// sense once a book has been located on disk.
+type bookMetadata struct {
+	epub.Metadata
+	Filename  string `json:"filename"`
+	FilePath  string `json:"filepath"`
+	FileSize  int64  `json:"filesize"`
+	CoverPath string `json:"cover_path,omitempty"`
+	WordCount int    `json:"word_count"`
+}
+
+// bookExtra is the per-book result of the work done inside bookProcess,
+// carried from a WalkDir worker goroutine to processDirectory's result loop
+// via Result.Extra.
+type bookExtra struct {
+	CoverPath string
+	WordCount int
+}
+
+// bookProcess returns an epub.WalkOptions.Process func that extracts a
+// book's cover (if coversDir is set) and plain text (for its word count,
+// and written to textDir if set), using the Reader the worker pool already
+// has open rather than reopening and reparsing the file. Errors from the
+// two steps are joined so neither suppresses the other.
+func bookProcess(coversDir, textDir string) func(string, *epub.Reader) (any, error) {
+	return func(path string, r *epub.Reader) (any, error) {
+		var extra bookExtra
+		var errs []string
+
+		if coversDir != "" {
+			coverPath, err := saveCover(path, r, coversDir)
+			if err != nil && err != epub.ErrNoCover {
+				errs = append(errs, fmt.Sprintf("cover: %v", err))
+			}
+			extra.CoverPath = coverPath
+		}
+
+		wordCount, err := extractText(path, r, textDir)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("text: %v", err))
+		}
+		extra.WordCount = wordCount
+
+		if len(errs) > 0 {
+			return extra, fmt.Errorf("%s", strings.Join(errs, "; "))
+		}
+		return extra, nil
+	}
+}
+
+// extractText renders r's spine as plain text and returns its word count.
+// If textDir is non-empty, the text is also written there as
+// "<book>.txt".
+func extractText(path string, r *epub.Reader, textDir string) (int, error) {
+	var buf bytes.Buffer
+	if err := r.PlainText(&buf); err != nil {
+		return 0, err
+	}
+
+	if textDir != "" {
+		if err := os.MkdirAll(textDir, 0o755); err != nil {
+			return 0, err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + ".txt"
+		if err := os.WriteFile(filepath.Join(textDir, name), buf.Bytes(), 0o644); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(strings.Fields(buf.String())), nil
+}
+
+// saveCover extracts r's cover image, if any, and writes it to coversDir,
+// naming it after the book's first identifier (falling back to its
+// filename) and an extension derived from the cover's MIME type. It
+// returns the path written to, or "" (with epub.ErrNoCover) if the book
+// declares no cover.
+func saveCover(path string, r *epub.Reader, coversDir string) (string, error) {
+	data, mediaType, err := r.Cover()
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	for _, id := range r.Metadata.Identifiers {
+		if id.Value != "" {
+			name = id.Value
+			break
+		}
+	}
+	name = strings.Map(func(c rune) rune {
+		if c == '/' || c == '\\' || c == ':' {
+			return '_'
+		}
+		return c
+	}, name)
+
+	ext := ".bin"
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		ext = exts[0]
+	}
+
+	if err := os.MkdirAll(coversDir, 0o755); err != nil {
+		return "", err
+	}
+	coverPath := filepath.Join(coversDir, name+ext)
+	if err := os.WriteFile(coverPath, data, 0o644); err != nil {
+		return "", err
+	}
+	return coverPath, nil
+}
+
+// processDirectory scans dirPath for EPUB files using a bounded pool of
+// workers and streams each book's metadata to outputFile (or stdout) as a
+// JSON array, so memory stays bounded and partial output survives a crash.
+// Progress (files done/total, current path) is reported to stderr unless
+// quiet is set.
+func processDirectory(dirPath, outputFile string, prettyPrint bool, coversDir, textDir string, workers int, quiet bool) error {
+	// Process runs inside WalkDir's worker goroutines, reusing the Reader
+	// they already have open rather than reopening and reparsing each book
+	// again here. Word count is always reported, so the plain-text pass
+	// always runs even if -text wasn't given; it just skips the write.
+	opts := epub.WalkOptions{Workers: workers, Process: bookProcess(coversDir, textDir)}
+
+	results, err := epub.WalkDir(context.Background(), dirPath, opts)
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer
+	if outputFile == "" {
+		output = os.Stdout
+	} else {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	writer := newJSONArrayWriter(output, prettyPrint)
+
+	var count int
+	for res := range results {
+		count++
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", res.Index, res.Total, res.Path)
+		}
+		if res.Err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", res.Path, res.Err)
+			continue
+		}
+
+		metadata := bookMetadata{
+			Metadata: res.Metadata,
+			Filename: filepath.Base(res.Path),
+			FilePath: res.Path,
+		}
+		if fi, err := os.Stat(res.Path); err == nil {
+			metadata.FileSize = fi.Size()
+		}
+		if res.ProcessErr != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", res.Path, res.ProcessErr)
+		}
+		if extra, ok := res.Extra.(bookExtra); ok {
+			metadata.CoverPath = extra.CoverPath
+			metadata.WordCount = extra.WordCount
+		}
+
+		if err := writer.Write(metadata); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return fmt.Errorf("no epub files found in %s", dirPath)
+	}
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		runValidate(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "set" {
+		runSet(os.Args[2:])
+		return
+	}
+
+	dirPath := flag.String("dir", "", "Directory containing epub files")
+	outputFile := flag.String("output", "", "Output JSON file (defaults to stdout)")
+	prettyPrint := flag.Bool("pretty", true, "Pretty-print the JSON output")
+	coversDir := flag.String("covers", "", "Directory to write extracted cover images to (defaults to none)")
+	textDir := flag.String("text", "", "Directory to write extracted plain text to (defaults to none)")
+	workers := flag.Int("workers", 0, "Number of books to parse concurrently (defaults to runtime.NumCPU())")
+	quiet := flag.Bool("quiet", false, "Suppress progress output on stderr")
+
+	flag.Parse()
+
+	if *dirPath == "" {
+		if flag.NArg() > 0 {
+			*dirPath = flag.Arg(0)
+		} else {
+			fmt.Println("Usage: epub-metadata-extractor -dir <directory> [-output <file>] [-pretty=true|false] [-covers <dir>] [-text <dir>] [-workers <n>] [-quiet]")
+			flag.PrintDefaults()
+			os.Exit(1)
+		}
+	}
+
+	if err := processDirectory(*dirPath, *outputFile, *prettyPrint, *coversDir, *textDir, *workers, *quiet); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
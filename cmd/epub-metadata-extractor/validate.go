@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fazalmajid/epub/pkg/epub"
+)
+
+// fileIssues is one book's validation results, for the "validate"
+// subcommand's JSON output.
+type fileIssues struct {
+	File   string       `json:"file"`
+	Issues []epub.Issue `json:"issues"`
+}
+
+// runValidate implements the "validate" subcommand: it runs epub.Validate
+// against each file given on the command line and reports the issues
+// found. It exits with a non-zero status if any file has an error-level
+// issue, so it can drive a CI check.
+func runValidate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	jsonOut := fs.Bool("json", true, "emit issues as JSON")
+	fs.Parse(args)
+
+	files := fs.Args()
+	if len(files) == 0 {
+		fmt.Println("Usage: epub-metadata-extractor validate [-json=true|false] <book.epub> [book2.epub ...]")
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	var results []fileIssues
+
+	for _, path := range files {
+		r, err := epub.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening %s: %v\n", path, err)
+			exitCode = 1
+			continue
+		}
+		issues := epub.Validate(&r.Reader)
+		r.Close()
+
+		for _, issue := range issues {
+			if issue.Severity == epub.SeverityError {
+				exitCode = 1
+			}
+		}
+		results = append(results, fileIssues{File: path, Issues: issues})
+	}
+
+	if *jsonOut {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(results)
+	} else {
+		for _, fi := range results {
+			if len(fi.Issues) == 0 {
+				fmt.Printf("%s: OK\n", fi.File)
+				continue
+			}
+			for _, issue := range fi.Issues {
+				fmt.Printf("%s: [%s] %s: %s\n", fi.File, issue.Severity, issue.Location, issue.Message)
+			}
+		}
+	}
+
+	os.Exit(exitCode)
+}
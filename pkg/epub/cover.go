@@ -0,0 +1,69 @@
+package epub
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrNoCover is returned by Cover when the EPUB declares no cover image.
+var ErrNoCover = errors.New("epub: no cover image found")
+
+// coverItem returns the manifest item for the publication's cover image,
+// looking for EPUB 3's properties="cover-image" first and falling back to
+// EPUB 2's <meta name="cover" content="id"/>.
+func (r *Reader) coverItem() *manifestItem {
+	for i, item := range r.Package.Manifest {
+		for _, p := range strings.Fields(item.Properties) {
+			if p == "cover-image" {
+				return &r.Package.Manifest[i]
+			}
+		}
+	}
+
+	var coverID string
+	for _, m := range r.Package.Metadata.Meta {
+		if m.Name == "cover" {
+			coverID = m.Content
+			break
+		}
+	}
+	if coverID == "" {
+		return nil
+	}
+	for i, item := range r.Package.Manifest {
+		if item.ID == coverID {
+			return &r.Package.Manifest[i]
+		}
+	}
+	return nil
+}
+
+// Cover returns the raw bytes and MIME type of the publication's cover
+// image, as declared by an EPUB 3 properties="cover-image" manifest item or
+// an EPUB 2 <meta name="cover">. It returns ErrNoCover if the EPUB declares
+// none.
+func (r *Reader) Cover() ([]byte, string, error) {
+	item := r.coverItem()
+	if item == nil {
+		return nil, "", ErrNoCover
+	}
+
+	f := r.hrefFile(item.Href)
+	if f == nil {
+		return nil, "", errors.New("epub: cover item " + r.resolveHref(item.Href) + " not found in zip")
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, item.MediaType, nil
+}
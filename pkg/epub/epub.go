@@ -0,0 +1,160 @@
+// Package epub parses EPUB ebook files: the OCF container, the OPF package
+// document, and the metadata it carries. It mirrors the standard library's
+// archive/zip package so callers can read EPUBs from disk, from an
+// io.ReaderAt backed by an HTTP response, or from an in-memory buffer.
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path"
+)
+
+// Container models META-INF/container.xml, the OCF entry point that names
+// the root OPF package document.
+type Container struct {
+	XMLName  xml.Name `xml:"container"`
+	RootFile struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// Reader reads the contents of a single EPUB file. It parses the OCF
+// container and the OPF package document at construction time, exposing the
+// decoded metadata through the Metadata field.
+type Reader struct {
+	zr *zip.Reader
+
+	Container Container
+	Package   Package
+	Metadata  Metadata
+
+	opfPath string
+}
+
+// ReadCloser is like Reader but obtained from opening a file on disk. It
+// must be closed when no longer needed.
+type ReadCloser struct {
+	Reader
+	f *os.File
+}
+
+// Open opens the EPUB file named by path for reading.
+func Open(path string) (*ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r, err := NewReader(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	rc := &ReadCloser{f: f}
+	rc.Reader = *r
+	return rc, nil
+}
+
+// Close closes the EPUB file, rendering it unusable for reading.
+func (rc *ReadCloser) Close() error {
+	return rc.f.Close()
+}
+
+// NewReader returns a new Reader reading an EPUB from ra, which is assumed
+// to have the given size in bytes. It mirrors archive/zip.NewReader so
+// EPUBs can be parsed from HTTP bodies, embedded blobs, or in-memory
+// buffers without hitting disk.
+func NewReader(ra io.ReaderAt, size int64) (*Reader, error) {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reader{zr: zr}
+
+	if err := r.readContainer(); err != nil {
+		return nil, err
+	}
+	if err := r.readPackage(); err != nil {
+		return nil, err
+	}
+	r.readMetadata()
+
+	return r, nil
+}
+
+// file returns the zip entry with the given name, or nil if it doesn't
+// exist.
+func (r *Reader) file(name string) *zip.File {
+	for _, f := range r.zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// resolveHref resolves a manifest item's href (an IRI reference, which may
+// be percent-encoded, e.g. "cover%20image.jpg") against the directory the
+// OPF lives in, returning the zip entry name it refers to. Hrefs that fail
+// to decode are resolved as given, since most are plain paths with nothing
+// to unescape.
+func (r *Reader) resolveHref(href string) string {
+	if decoded, err := url.PathUnescape(href); err == nil {
+		href = decoded
+	}
+	return path.Join(path.Dir(r.opfPath), href)
+}
+
+// hrefFile is like file, but resolves href as resolveHref would first.
+func (r *Reader) hrefFile(href string) *zip.File {
+	return r.file(r.resolveHref(href))
+}
+
+func (r *Reader) readContainer() error {
+	cf := r.file("META-INF/container.xml")
+	if cf == nil {
+		return errors.New("epub: container.xml not found")
+	}
+	rc, err := cf.Open()
+	if err != nil {
+		return err
+	}
+	xr, err := xml11To10Reader(rc)
+	if err != nil {
+		return err
+	}
+	if err := xml.NewDecoder(xr).Decode(&r.Container); err != nil {
+		return err
+	}
+	if r.Container.RootFile.FullPath == "" {
+		return errors.New("epub: OPF file path not found in container.xml")
+	}
+	r.opfPath = r.Container.RootFile.FullPath
+	return nil
+}
+
+func (r *Reader) readPackage() error {
+	pf := r.file(r.opfPath)
+	if pf == nil {
+		return errors.New("epub: OPF file not found")
+	}
+	rc, err := pf.Open()
+	if err != nil {
+		return err
+	}
+	xr, err := xml11To10Reader(rc)
+	if err != nil {
+		return err
+	}
+	return xml.NewDecoder(xr).Decode(&r.Package)
+}
@@ -0,0 +1,174 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// opfWithEncodedCover declares its cover via a percent-encoded href, as is
+// legal for the IRI references OPF manifest items use.
+const opfWithEncodedCover = `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Encoded Href Book</dc:title>
+    <dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="content" href="content%20page.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover-image" href="cover%20image.jpg" media-type="image/jpeg" properties="cover-image"/>
+  </manifest>
+  <spine>
+    <itemref idref="content"/>
+  </spine>
+</package>
+`
+
+// writeTestEPUBWithEncodedHrefs builds an EPUB whose manifest hrefs are
+// percent-encoded but whose zip entries are named with the literal
+// (decoded) characters, as real-world EPUBs do.
+func writeTestEPUBWithEncodedHrefs(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "encoded.epub")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create fixture: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		t.Fatalf("write mimetype: %v", err)
+	}
+	mw.Write([]byte("application/epub+zip"))
+
+	cw, err := zw.Create("META-INF/container.xml")
+	if err != nil {
+		t.Fatalf("write container.xml: %v", err)
+	}
+	cw.Write([]byte(`<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`))
+
+	ow, err := zw.Create("content.opf")
+	if err != nil {
+		t.Fatalf("write content.opf: %v", err)
+	}
+	ow.Write([]byte(opfWithEncodedCover))
+
+	pw, err := zw.Create("content page.xhtml")
+	if err != nil {
+		t.Fatalf("write content page.xhtml: %v", err)
+	}
+	pw.Write([]byte("<html><body><p>Hello</p></body></html>"))
+
+	iw, err := zw.Create("cover image.jpg")
+	if err != nil {
+		t.Fatalf("write cover image.jpg: %v", err)
+	}
+	iw.Write([]byte("fake-jpeg-bytes"))
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close fixture zip: %v", err)
+	}
+	return path
+}
+
+func TestCover_PercentEncodedHref(t *testing.T) {
+	path := writeTestEPUBWithEncodedHrefs(t)
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	data, mediaType, err := r.Cover()
+	if err != nil {
+		t.Fatalf("Cover: %v", err)
+	}
+	if string(data) != "fake-jpeg-bytes" {
+		t.Errorf("Cover data = %q, want %q", data, "fake-jpeg-bytes")
+	}
+	if mediaType != "image/jpeg" {
+		t.Errorf("Cover mediaType = %q, want image/jpeg", mediaType)
+	}
+}
+
+func TestValidate_PercentEncodedHrefResolves(t *testing.T) {
+	path := writeTestEPUBWithEncodedHrefs(t)
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	for _, iss := range Validate(&r.Reader) {
+		if iss.Severity == SeverityError {
+			t.Errorf("unexpected error-level issue for a valid, percent-encoded-href book: %+v", iss)
+		}
+	}
+}
+
+func TestSpine_PercentEncodedHref(t *testing.T) {
+	path := writeTestEPUBWithEncodedHrefs(t)
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	items := r.Spine()
+	if len(items) != 1 {
+		t.Fatalf("Spine() = %+v, want 1 item", items)
+	}
+	rc, err := items[0].Open()
+	if err != nil {
+		t.Fatalf("Open spine item: %v", err)
+	}
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	buf.ReadFrom(rc)
+	if buf.String() != "<html><body><p>Hello</p></body></html>" {
+		t.Errorf("spine item content = %q, want the fixture's content page.xhtml body", buf.String())
+	}
+}
+
+func TestWriter_SetCoverReplacesPercentEncodedExistingCover(t *testing.T) {
+	path := writeTestEPUBWithEncodedHrefs(t)
+
+	w, err := OpenWriter(path)
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	defer w.Close()
+
+	w.SetCover([]byte("new-cover-bytes"), "image/png")
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	r := readBack(t, buf.Bytes())
+	if len(r.Package.Manifest) != 2 {
+		t.Fatalf("Manifest = %+v, want 2 items (existing cover item reused, not duplicated)", r.Package.Manifest)
+	}
+
+	data, mediaType, err := r.Cover()
+	if err != nil {
+		t.Fatalf("Cover: %v", err)
+	}
+	if string(data) != "new-cover-bytes" {
+		t.Errorf("Cover data = %q, want %q", data, "new-cover-bytes")
+	}
+	if mediaType != "image/png" {
+		t.Errorf("Cover mediaType = %q, want image/png", mediaType)
+	}
+}
@@ -0,0 +1,161 @@
+package epub
+
+import "strings"
+
+// Creator is a dc:creator or dc:contributor, resolved from either EPUB 2's
+// opf:role/opf:file-as attributes or EPUB 3's <meta refines> refinements.
+type Creator struct {
+	Name   string `json:"name"`
+	FileAs string `json:"file_as,omitempty"`
+	Role   string `json:"role,omitempty"` // MARC relator code, e.g. "aut", "edt"
+}
+
+// Identifier is a dc:identifier, resolved from either EPUB 2's opf:scheme
+// attribute or EPUB 3's <meta refines property="identifier-type">.
+type Identifier struct {
+	Value    string `json:"value"`
+	Scheme   string `json:"scheme,omitempty"` // e.g. "ISBN", "UUID", "URI"
+	IsUnique bool   `json:"is_unique,omitempty"`
+}
+
+// Date is a dc:date, with the event it records (EPUB 2's opf:event
+// attribute: "publication", "modification", "creation").
+type Date struct {
+	Value string `json:"value"`
+	Event string `json:"event,omitempty"`
+}
+
+// Series describes an EPUB 3 belongs-to-collection / group-position pair.
+type Series struct {
+	Name     string `json:"name"`
+	Position string `json:"position,omitempty"`
+}
+
+// Metadata holds the metadata decoded from an EPUB's OPF package document,
+// covering both EPUB 2 attributes and EPUB 3 meta refinements.
+type Metadata struct {
+	Title       string            `json:"title"`
+	Creators    []Creator         `json:"creators"`
+	Identifiers []Identifier      `json:"identifiers"`
+	Language    string            `json:"language,omitempty"`
+	Publisher   string            `json:"publisher,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Subjects    []string          `json:"subjects,omitempty"`
+	Dates       []Date            `json:"dates,omitempty"`
+	Rights      string            `json:"rights,omitempty"`
+	Modified    string            `json:"modified,omitempty"` // dcterms:modified
+	Series      *Series           `json:"series,omitempty"`
+	Rendition   map[string]string `json:"rendition,omitempty"`
+}
+
+// readMetadata resolves r.Package's raw metadata block into r.Metadata,
+// matching opf:role/opf:file-as/opf:scheme/opf:event attributes (EPUB 2)
+// and <meta refines="#id"> refinements (EPUB 3) to the elements they
+// describe.
+func (r *Reader) readMetadata() {
+	md := &r.Package.Metadata
+
+	m := Metadata{
+		Subjects: append([]string{}, md.Subject...),
+	}
+
+	if len(md.Title) > 0 {
+		m.Title = strings.TrimSpace(md.Title[0].Value)
+	}
+	if len(md.Language) > 0 {
+		m.Language = md.Language[0]
+	}
+	if len(md.Publisher) > 0 {
+		m.Publisher = md.Publisher[0]
+	}
+	if len(md.Description) > 0 {
+		m.Description = md.Description[0]
+	}
+	if len(md.Rights) > 0 {
+		m.Rights = md.Rights[0]
+	}
+
+	for _, c := range md.Creator {
+		refines := md.refinements(c.ID)
+		creator := Creator{
+			Name:   strings.TrimSpace(c.Value),
+			FileAs: c.FileAs,
+			Role:   c.Role,
+		}
+		if creator.FileAs == "" {
+			if ref, ok := refines["file-as"]; ok {
+				creator.FileAs = strings.TrimSpace(ref.Value)
+			}
+		}
+		if creator.Role == "" {
+			if ref, ok := refines["role"]; ok {
+				creator.Role = strings.TrimSpace(ref.Value)
+			}
+		}
+		m.Creators = append(m.Creators, creator)
+	}
+
+	for _, i := range md.Identifier {
+		refines := md.refinements(i.ID)
+		ident := Identifier{
+			Value:    strings.TrimSpace(i.Value),
+			Scheme:   i.Scheme,
+			IsUnique: i.ID != "" && i.ID == r.Package.UniqueIdentifier,
+		}
+		if ident.Scheme == "" {
+			if ref, ok := refines["identifier-type"]; ok {
+				ident.Scheme = strings.TrimSpace(ref.Value)
+			}
+		}
+		m.Identifiers = append(m.Identifiers, ident)
+	}
+
+	for _, d := range md.Date {
+		m.Dates = append(m.Dates, Date{
+			Value: strings.TrimSpace(d.Value),
+			Event: d.Event,
+		})
+	}
+
+	if modified, ok := md.global("dcterms:modified"); ok {
+		m.Modified = modified
+	}
+
+	m.Series = readSeries(md)
+	m.Rendition = readRendition(md)
+
+	r.Metadata = m
+}
+
+// readSeries looks for a top-level <meta property="belongs-to-collection">
+// and pulls its group-position refinement, if any.
+func readSeries(md *rawMetadata) *Series {
+	for _, meta := range md.Meta {
+		if meta.Refines != "" || meta.Property != "belongs-to-collection" {
+			continue
+		}
+		s := &Series{Name: strings.TrimSpace(meta.Value)}
+		if ref, ok := md.refinements(meta.ID)["group-position"]; ok {
+			s.Position = strings.TrimSpace(ref.Value)
+		}
+		return s
+	}
+	return nil
+}
+
+// readRendition collects the top-level rendition:* meta properties (layout,
+// orientation, spread, flow, ...) into a map keyed by the part after the
+// colon.
+func readRendition(md *rawMetadata) map[string]string {
+	var out map[string]string
+	for _, meta := range md.Meta {
+		if meta.Refines != "" || !strings.HasPrefix(meta.Property, "rendition:") {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[strings.TrimPrefix(meta.Property, "rendition:")] = strings.TrimSpace(meta.Value)
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package epub
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+// parseOPF decodes an OPF document body (the full <package> element) into a
+// Reader with its Metadata resolved, as readMetadata would see it coming out
+// of readPackage.
+func parseOPF(t *testing.T, opfXML string) *Reader {
+	t.Helper()
+	var pkg Package
+	if err := xml.Unmarshal([]byte(opfXML), &pkg); err != nil {
+		t.Fatalf("unmarshal OPF: %v", err)
+	}
+	r := &Reader{Package: pkg}
+	r.readMetadata()
+	return r
+}
+
+func TestReadMetadata_Creator(t *testing.T) {
+	tests := []struct {
+		name string
+		opf  string
+		want Creator
+	}{
+		{
+			name: "epub2 attributes",
+			opf:  `<dc:creator id="cre1" opf:role="aut" opf:file-as="Doe, Jane">Jane Doe</dc:creator>`,
+			want: Creator{Name: "Jane Doe", FileAs: "Doe, Jane", Role: "aut"},
+		},
+		{
+			name: "epub3 refinements",
+			opf: `<dc:creator id="cre1">Jane Doe</dc:creator>
+				<meta refines="#cre1" property="role">aut</meta>
+				<meta refines="#cre1" property="file-as">Doe, Jane</meta>`,
+			want: Creator{Name: "Jane Doe", FileAs: "Doe, Jane", Role: "aut"},
+		},
+		{
+			name: "epub2 attributes win over refinements when both present",
+			opf: `<dc:creator id="cre1" opf:role="aut" opf:file-as="Doe, Jane">Jane Doe</dc:creator>
+				<meta refines="#cre1" property="role">edt</meta>
+				<meta refines="#cre1" property="file-as">Wrong, Name</meta>`,
+			want: Creator{Name: "Jane Doe", FileAs: "Doe, Jane", Role: "aut"},
+		},
+		{
+			name: "no role or file-as at all",
+			opf:  `<dc:creator id="cre1">Jane Doe</dc:creator>`,
+			want: Creator{Name: "Jane Doe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := parseOPF(t, sprintfOPF(tt.opf, "uid"))
+			if len(r.Metadata.Creators) != 1 {
+				t.Fatalf("got %d creators, want 1: %+v", len(r.Metadata.Creators), r.Metadata.Creators)
+			}
+			if got := r.Metadata.Creators[0]; got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMetadata_Identifier(t *testing.T) {
+	tests := []struct {
+		name string
+		opf  string
+		want Identifier
+	}{
+		{
+			name: "epub2 scheme attribute, marked unique",
+			opf:  `<dc:identifier id="bookid" opf:scheme="ISBN">978-0-13-468599-1</dc:identifier>`,
+			want: Identifier{Value: "978-0-13-468599-1", Scheme: "ISBN", IsUnique: true},
+		},
+		{
+			name: "epub3 identifier-type refinement, marked unique",
+			opf: `<dc:identifier id="bookid">978-0-13-468599-1</dc:identifier>
+				<meta refines="#bookid" property="identifier-type">ISBN</meta>`,
+			want: Identifier{Value: "978-0-13-468599-1", Scheme: "ISBN", IsUnique: true},
+		},
+		{
+			name: "non-unique-identifier id is not marked unique",
+			opf:  `<dc:identifier id="other" opf:scheme="URI">urn:uuid:1234</dc:identifier>`,
+			want: Identifier{Value: "urn:uuid:1234", Scheme: "URI", IsUnique: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := parseOPF(t, sprintfOPF(tt.opf, "bookid"))
+			if len(r.Metadata.Identifiers) != 1 {
+				t.Fatalf("got %d identifiers, want 1: %+v", len(r.Metadata.Identifiers), r.Metadata.Identifiers)
+			}
+			if got := r.Metadata.Identifiers[0]; got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMetadata_Date(t *testing.T) {
+	r := parseOPF(t, sprintfOPF(`<dc:date opf:event="publication">2020-01-15</dc:date>`, "uid"))
+	want := []Date{{Value: "2020-01-15", Event: "publication"}}
+	if !reflect.DeepEqual(r.Metadata.Dates, want) {
+		t.Errorf("got %+v, want %+v", r.Metadata.Dates, want)
+	}
+}
+
+func TestReadMetadata_Series(t *testing.T) {
+	r := parseOPF(t, sprintfOPF(`
+		<meta id="series" property="belongs-to-collection">The Foundation Series</meta>
+		<meta refines="#series" property="group-position">2</meta>`, "uid"))
+
+	want := &Series{Name: "The Foundation Series", Position: "2"}
+	if !reflect.DeepEqual(r.Metadata.Series, want) {
+		t.Errorf("got %+v, want %+v", r.Metadata.Series, want)
+	}
+}
+
+func TestReadMetadata_SeriesAbsent(t *testing.T) {
+	r := parseOPF(t, sprintfOPF(`<dc:title>No Series</dc:title>`, "uid"))
+	if r.Metadata.Series != nil {
+		t.Errorf("got %+v, want nil", r.Metadata.Series)
+	}
+}
+
+func TestReadMetadata_Rendition(t *testing.T) {
+	r := parseOPF(t, sprintfOPF(`
+		<meta property="rendition:layout">pre-paginated</meta>
+		<meta property="rendition:orientation">landscape</meta>`, "uid"))
+
+	want := map[string]string{"layout": "pre-paginated", "orientation": "landscape"}
+	if !reflect.DeepEqual(r.Metadata.Rendition, want) {
+		t.Errorf("got %+v, want %+v", r.Metadata.Rendition, want)
+	}
+}
+
+// sprintfOPF wraps an inner <metadata> body in a full OPF document, avoiding
+// a literal %-using fmt.Sprintf call on XML containing "%" in attribute
+// values by using a fixed, simple substitution instead.
+func sprintfOPF(inner, uniqueID string) string {
+	return `<package xmlns="http://www.idpf.org/2007/opf" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="` + uniqueID + `"><metadata>` + inner + `</metadata></package>`
+}
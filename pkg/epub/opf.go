@@ -0,0 +1,132 @@
+package epub
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Package models the OPF package document: the unique-identifier attribute
+// and the full metadata block, including EPUB 3 meta refinements and EPUB 2
+// opf: attributes (e.g. opf:role, opf:file-as, opf:scheme, opf:event,
+// bound to the namespace http://www.idpf.org/2007/opf).
+type Package struct {
+	XMLName          xml.Name       `xml:"package"`
+	Version          string         `xml:"version,attr"`
+	UniqueIdentifier string         `xml:"unique-identifier,attr"`
+	Metadata         rawMetadata    `xml:"metadata"`
+	Manifest         []manifestItem `xml:"manifest>item"`
+	Spine            spine          `xml:"spine"`
+}
+
+// spine is the OPF reading order: the linear sequence of manifest items
+// that make up the publication, plus (EPUB 2 only) a reference to the NCX
+// table of contents.
+type spine struct {
+	Toc   string         `xml:"toc,attr"`
+	Items []spineItemRef `xml:"itemref"`
+}
+
+// spineItemRef is a single <itemref> in the spine, pointing at a manifest
+// item by id.
+type spineItemRef struct {
+	IDRef string `xml:"idref,attr"`
+}
+
+// manifestItem is a <item> element of the OPF manifest, listing one file
+// that makes up the publication.
+type manifestItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// rawMetadata is the metadata block as it appears in the OPF, before
+// refinements and attributes are resolved into the public Metadata type.
+type rawMetadata struct {
+	Title       []titleElem      `xml:"title"`
+	Creator     []creatorElem    `xml:"creator"`
+	Identifier  []identifierElem `xml:"identifier"`
+	Language    []string         `xml:"language"`
+	Publisher   []string         `xml:"publisher"`
+	Description []string         `xml:"description"`
+	Subject     []string         `xml:"subject"`
+	Date        []dateElem       `xml:"date"`
+	Rights      []string         `xml:"rights"`
+	Meta        []metaElem       `xml:"meta"`
+}
+
+type titleElem struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+// creatorElem is a dc:creator (or dc:contributor) element. Role and FileAs
+// are populated from EPUB 2's opf:role / opf:file-as attributes; EPUB 3
+// packages instead refine the element via a separate <meta refines="#id">.
+type creatorElem struct {
+	ID     string `xml:"id,attr"`
+	Role   string `xml:"http://www.idpf.org/2007/opf role,attr"`
+	FileAs string `xml:"http://www.idpf.org/2007/opf file-as,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// identifierElem is a dc:identifier element. Scheme is populated from
+// EPUB 2's opf:scheme attribute; EPUB 3 packages instead refine the
+// element via a <meta refines="#id" property="identifier-type">.
+type identifierElem struct {
+	ID     string `xml:"id,attr"`
+	Scheme string `xml:"http://www.idpf.org/2007/opf scheme,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// dateElem is a dc:date element. Event is populated from EPUB 2's
+// opf:event attribute (e.g. "publication", "modification", "creation").
+type dateElem struct {
+	ID    string `xml:"id,attr"`
+	Event string `xml:"http://www.idpf.org/2007/opf event,attr"`
+	Value string `xml:",chardata"`
+}
+
+// metaElem is an EPUB 3 <meta> element, used both for refinements
+// (refines="#id" property="...") and for standalone global properties such
+// as dcterms:modified, belongs-to-collection and rendition:*. It also
+// matches EPUB 2's <meta name="..." content="..."/> form.
+type metaElem struct {
+	ID       string `xml:"id,attr"`
+	Property string `xml:"property,attr"`
+	Refines  string `xml:"refines,attr"`
+	Scheme   string `xml:"scheme,attr"`
+	Name     string `xml:"name,attr"`
+	Content  string `xml:"content,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// refinements returns the meta elements that refine the element with the
+// given id (i.e. refines="#id"), keyed by property name.
+func (md *rawMetadata) refinements(id string) map[string]metaElem {
+	if id == "" {
+		return nil
+	}
+	var out map[string]metaElem
+	for _, m := range md.Meta {
+		if m.Refines == "#"+id {
+			if out == nil {
+				out = make(map[string]metaElem)
+			}
+			out[m.Property] = m
+		}
+	}
+	return out
+}
+
+// global returns the value of the first top-level <meta property="name">
+// element, i.e. one that does not refine another element.
+func (md *rawMetadata) global(property string) (string, bool) {
+	for _, m := range md.Meta {
+		if m.Refines == "" && m.Property == property {
+			return strings.TrimSpace(m.Value), true
+		}
+	}
+	return "", false
+}
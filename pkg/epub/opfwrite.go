@@ -0,0 +1,117 @@
+package epub
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// xmlEscape returns s with the characters XML requires escaped in both
+// text content and quoted attribute values (&, <, >, ' and ").
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// attr renders a single quoted XML attribute, or "" if value is empty.
+func attr(name, value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf(` %s="%s"`, name, xmlEscape(value))
+}
+
+// render serializes pkg back into an OPF package document. Element and
+// attribute order is fixed by this function (not by the order fields were
+// parsed in), so round-tripping the same Package twice produces a
+// byte-identical, minimal-diff OPF.
+func (pkg *Package) render() []byte {
+	var b bytes.Buffer
+
+	b.WriteString(xml.Header)
+	version := pkg.Version
+	if version == "" {
+		version = "3.0"
+	}
+	fmt.Fprintf(&b, "<package xmlns=\"http://www.idpf.org/2007/opf\" version=\"%s\"%s>\n",
+		xmlEscape(version), attr("unique-identifier", pkg.UniqueIdentifier))
+
+	b.WriteString("  <metadata xmlns:dc=\"http://purl.org/dc/elements/1.1/\" xmlns:opf=\"http://www.idpf.org/2007/opf\">\n")
+	pkg.Metadata.render(&b)
+	b.WriteString("  </metadata>\n")
+
+	b.WriteString("  <manifest>\n")
+	for _, item := range pkg.Manifest {
+		fmt.Fprintf(&b, "    <item%s%s%s%s/>\n",
+			attr("id", item.ID), attr("href", item.Href), attr("media-type", item.MediaType), attr("properties", item.Properties))
+	}
+	b.WriteString("  </manifest>\n")
+
+	fmt.Fprintf(&b, "  <spine%s>\n", attr("toc", pkg.Spine.Toc))
+	for _, ref := range pkg.Spine.Items {
+		fmt.Fprintf(&b, "    <itemref%s/>\n", attr("idref", ref.IDRef))
+	}
+	b.WriteString("  </spine>\n")
+
+	b.WriteString("</package>\n")
+	return b.Bytes()
+}
+
+func (md *rawMetadata) render(b *bytes.Buffer) {
+	for _, t := range md.Title {
+		fmt.Fprintf(b, "    <dc:title%s>%s</dc:title>\n", attr("id", t.ID), xmlEscape(t.Value))
+	}
+	for _, c := range md.Creator {
+		fmt.Fprintf(b, "    <dc:creator%s%s%s>%s</dc:creator>\n",
+			attr("id", c.ID), attr("opf:role", c.Role), attr("opf:file-as", c.FileAs), xmlEscape(c.Value))
+	}
+	for _, i := range md.Identifier {
+		fmt.Fprintf(b, "    <dc:identifier%s%s>%s</dc:identifier>\n",
+			attr("id", i.ID), attr("opf:scheme", i.Scheme), xmlEscape(i.Value))
+	}
+	for _, l := range md.Language {
+		fmt.Fprintf(b, "    <dc:language>%s</dc:language>\n", xmlEscape(l))
+	}
+	for _, p := range md.Publisher {
+		fmt.Fprintf(b, "    <dc:publisher>%s</dc:publisher>\n", xmlEscape(p))
+	}
+	for _, d := range md.Description {
+		fmt.Fprintf(b, "    <dc:description>%s</dc:description>\n", xmlEscape(d))
+	}
+	for _, s := range md.Subject {
+		fmt.Fprintf(b, "    <dc:subject>%s</dc:subject>\n", xmlEscape(s))
+	}
+	for _, d := range md.Date {
+		fmt.Fprintf(b, "    <dc:date%s%s>%s</dc:date>\n", attr("id", d.ID), attr("opf:event", d.Event), xmlEscape(d.Value))
+	}
+	for _, r := range md.Rights {
+		fmt.Fprintf(b, "    <dc:rights>%s</dc:rights>\n", xmlEscape(r))
+	}
+	for _, m := range md.Meta {
+		if m.Property == "" && m.Name != "" {
+			fmt.Fprintf(b, "    <meta%s%s/>\n", attr("name", m.Name), attr("content", m.Content))
+			continue
+		}
+		fmt.Fprintf(b, "    <meta%s%s%s%s>%s</meta>\n",
+			attr("property", m.Property), attr("refines", m.Refines), attr("scheme", m.Scheme), attr("id", m.ID), xmlEscape(m.Value))
+	}
+}
+
+// dropRefinesFor removes any <meta refines="#id"> entries whose id is in
+// ids, so replacing an element doesn't leave orphaned refinements behind
+// in the regenerated OPF.
+func (md *rawMetadata) dropRefinesFor(ids map[string]bool) {
+	if len(ids) == 0 {
+		return
+	}
+	kept := md.Meta[:0:0]
+	for _, m := range md.Meta {
+		if id, ok := strings.CutPrefix(m.Refines, "#"); ok && ids[id] {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	md.Meta = kept
+}
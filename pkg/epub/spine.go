@@ -0,0 +1,48 @@
+package epub
+
+import (
+	"errors"
+	"io"
+)
+
+// SpineItem is one document in an EPUB's reading order.
+type SpineItem struct {
+	ID        string
+	MediaType string
+
+	r    *Reader
+	href string // path within the zip archive, resolved relative to the OPF
+}
+
+// Open returns a reader for the spine item's content document.
+func (s SpineItem) Open() (io.ReadCloser, error) {
+	f := s.r.file(s.href)
+	if f == nil {
+		return nil, errors.New("epub: spine item " + s.href + " not found in archive")
+	}
+	return f.Open()
+}
+
+// Spine returns the EPUB's reading order: the content documents in the
+// order a reading system should present them.
+func (r *Reader) Spine() []SpineItem {
+	manifest := make(map[string]manifestItem, len(r.Package.Manifest))
+	for _, item := range r.Package.Manifest {
+		manifest[item.ID] = item
+	}
+
+	var items []SpineItem
+	for _, ref := range r.Package.Spine.Items {
+		item, ok := manifest[ref.IDRef]
+		if !ok {
+			continue
+		}
+		items = append(items, SpineItem{
+			ID:        item.ID,
+			MediaType: item.MediaType,
+			r:         r,
+			href:      r.resolveHref(item.Href),
+		})
+	}
+	return items
+}
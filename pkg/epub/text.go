@@ -0,0 +1,51 @@
+package epub
+
+import (
+	"html"
+	"io"
+	"regexp"
+)
+
+var (
+	scriptOrStyleTag = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?</\s*(script|style)\s*>`)
+	blockBoundaryTag = regexp.MustCompile(`(?i)</(p|div|li|h[1-6]|tr|blockquote)>|<br\s*/?>`)
+	anyTag           = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLines       = regexp.MustCompile(`\n{3,}`)
+)
+
+// PlainText writes the EPUB's spine documents to w, in reading order, as
+// plain UTF-8 text: XHTML tags are stripped (after dropping <script> and
+// <style> content entirely) and block-level elements become newlines.
+// Useful for indexing/search and for computing word counts.
+func (r *Reader) PlainText(w io.Writer) error {
+	for _, item := range r.Spine() {
+		rc, err := item.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(stripXHTML(content)); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stripXHTML removes markup from an XHTML document, leaving plain text
+// with newlines at block-level boundaries.
+func stripXHTML(doc []byte) []byte {
+	text := scriptOrStyleTag.ReplaceAll(doc, nil)
+	text = blockBoundaryTag.ReplaceAll(text, []byte("\n"))
+	text = anyTag.ReplaceAll(text, nil)
+	text = []byte(html.UnescapeString(string(text)))
+	text = blankLines.ReplaceAll(text, []byte("\n\n"))
+	return text
+}
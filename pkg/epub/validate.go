@@ -0,0 +1,171 @@
+package epub
+
+import (
+	"archive/zip"
+	"fmt"
+	"strings"
+)
+
+// Severity is how serious a validation Issue is.
+type Severity string
+
+const (
+	// SeverityError marks a violation of the EPUB spec that will likely
+	// cause reading systems to reject or mis-render the book.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a departure from best practice that most
+	// reading systems will tolerate.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a single problem found by Validate.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Location is an XPath-ish pointer to the offending file and element,
+	// e.g. "mimetype" or "OEBPS/content.opf#manifest/item[@id=\"cover\"]".
+	Location string `json:"location"`
+}
+
+func errorf(location, format string, args ...any) Issue {
+	return Issue{Severity: SeverityError, Location: location, Message: fmt.Sprintf(format, args...)}
+}
+
+func warningf(location, format string, args ...any) Issue {
+	return Issue{Severity: SeverityWarning, Location: location, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validate runs the structural checks an EPUB conformance tool would run
+// against an already-opened Reader: the mimetype entry, the OCF container,
+// manifest href resolution, spine idref resolution, the unique-identifier
+// attribute, and the presence of a navigation document.
+func Validate(r *Reader) []Issue {
+	var issues []Issue
+	issues = append(issues, validateMimetype(r)...)
+	issues = append(issues, validateContainer(r)...)
+	issues = append(issues, validateManifest(r)...)
+	issues = append(issues, validateSpine(r)...)
+	issues = append(issues, validateUniqueIdentifier(r)...)
+	issues = append(issues, validateNav(r)...)
+	return issues
+}
+
+func validateMimetype(r *Reader) []Issue {
+	var issues []Issue
+
+	if len(r.zr.File) == 0 {
+		return append(issues, errorf("mimetype", "epub archive is empty"))
+	}
+
+	first := r.zr.File[0]
+	if first.Name != "mimetype" {
+		issues = append(issues, errorf("mimetype", "mimetype must be the first entry in the zip, found %q", first.Name))
+		// Fall back to looking it up anywhere, so later checks still run.
+		first = r.file("mimetype")
+		if first == nil {
+			return append(issues, errorf("mimetype", "mimetype file not found"))
+		}
+	}
+
+	if first.Method != zip.Store {
+		issues = append(issues, errorf("mimetype", "mimetype entry must be STORED (uncompressed), not compressed"))
+	}
+
+	rc, err := first.Open()
+	if err != nil {
+		return append(issues, errorf("mimetype", "could not read mimetype entry: %v", err))
+	}
+	defer rc.Close()
+
+	buf := make([]byte, 64)
+	n, _ := rc.Read(buf)
+	if got := string(buf[:n]); got != "application/epub+zip" {
+		issues = append(issues, errorf("mimetype", "mimetype entry must contain exactly %q, found %q", "application/epub+zip", got))
+	}
+
+	return issues
+}
+
+func validateContainer(r *Reader) []Issue {
+	var issues []Issue
+	if r.file("META-INF/container.xml") == nil {
+		return append(issues, errorf("META-INF/container.xml", "container.xml not found"))
+	}
+	if r.Container.RootFile.FullPath == "" {
+		issues = append(issues, errorf("META-INF/container.xml", "container.xml references no rootfile"))
+	}
+	return issues
+}
+
+func validateManifest(r *Reader) []Issue {
+	var issues []Issue
+
+	for _, item := range r.Package.Manifest {
+		loc := fmt.Sprintf("%s#manifest/item[@id=%q]", r.opfPath, item.ID)
+
+		if strings.Contains(item.Href, "://") {
+			issues = append(issues, warningf(loc, "manifest item %q references a remote resource %q", item.ID, item.Href))
+			continue
+		}
+
+		if r.hrefFile(item.Href) == nil {
+			issues = append(issues, errorf(loc, "manifest item %q href %q does not resolve to a file in the archive", item.ID, item.Href))
+		}
+	}
+
+	return issues
+}
+
+func validateSpine(r *Reader) []Issue {
+	var issues []Issue
+
+	ids := make(map[string]bool, len(r.Package.Manifest))
+	for _, item := range r.Package.Manifest {
+		ids[item.ID] = true
+	}
+
+	for i, ref := range r.Package.Spine.Items {
+		if !ids[ref.IDRef] {
+			loc := fmt.Sprintf("%s#spine/itemref[%d]", r.opfPath, i)
+			issues = append(issues, errorf(loc, "spine itemref %q does not match any manifest item", ref.IDRef))
+		}
+	}
+
+	return issues
+}
+
+func validateUniqueIdentifier(r *Reader) []Issue {
+	loc := r.opfPath + "#package[@unique-identifier]"
+
+	if r.Package.UniqueIdentifier == "" {
+		return []Issue{errorf(loc, "package has no unique-identifier attribute")}
+	}
+
+	for _, id := range r.Package.Metadata.Identifier {
+		if id.ID == r.Package.UniqueIdentifier {
+			return nil
+		}
+	}
+
+	return []Issue{errorf(loc, "unique-identifier %q does not match the id of any dc:identifier", r.Package.UniqueIdentifier)}
+}
+
+func validateNav(r *Reader) []Issue {
+	for _, item := range r.Package.Manifest {
+		for _, p := range strings.Fields(item.Properties) {
+			if p == "nav" {
+				return nil
+			}
+		}
+	}
+
+	if r.Package.Spine.Toc != "" {
+		for _, item := range r.Package.Manifest {
+			if item.ID == r.Package.Spine.Toc {
+				return nil
+			}
+		}
+	}
+
+	return []Issue{warningf(r.opfPath, "no EPUB 3 nav document or EPUB 2 NCX found")}
+}
@@ -0,0 +1,186 @@
+package epub
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestEPUBWithBadMimetype builds an EPUB whose mimetype entry is
+// neither first nor stored uncompressed, to exercise validateMimetype.
+func writeTestEPUBWithBadMimetype(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bad-mimetype.epub")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create fixture: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	cw, err := zw.Create("META-INF/container.xml")
+	if err != nil {
+		t.Fatalf("write container.xml: %v", err)
+	}
+	cw.Write([]byte(`<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`))
+
+	ow, err := zw.Create("content.opf")
+	if err != nil {
+		t.Fatalf("write content.opf: %v", err)
+	}
+	ow.Write([]byte(minimalOPF))
+
+	ew, err := zw.Create("content.xhtml")
+	if err != nil {
+		t.Fatalf("write content.xhtml: %v", err)
+	}
+	ew.Write([]byte("fake-content.xhtml"))
+
+	// mimetype last, and deflated: both violations validateMimetype checks.
+	mw, err := zw.Create("mimetype")
+	if err != nil {
+		t.Fatalf("write mimetype: %v", err)
+	}
+	mw.Write([]byte("application/epub+zip"))
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close fixture zip: %v", err)
+	}
+	return path
+}
+
+func hasIssue(issues []Issue, severity Severity, location string) bool {
+	for _, iss := range issues {
+		if iss.Severity == severity && iss.Location == location {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidate_Clean(t *testing.T) {
+	path := writeTestEPUB(t, testBook{opf: minimalOPF, manifest: []string{"content.xhtml"}})
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	var errs []Issue
+	for _, iss := range Validate(&r.Reader) {
+		if iss.Severity == SeverityError {
+			errs = append(errs, iss)
+		}
+	}
+	if len(errs) != 0 {
+		t.Errorf("got error-level issues on a well-formed book: %+v", errs)
+	}
+}
+
+func TestValidate_DanglingManifestHref(t *testing.T) {
+	opf := `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Broken Book</dc:title>
+    <dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="content" href="content.xhtml" media-type="application/xhtml+xml"/>
+    <item id="missing" href="does-not-exist.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine>
+    <itemref idref="content"/>
+  </spine>
+</package>
+`
+	path := writeTestEPUB(t, testBook{opf: opf, manifest: []string{"content.xhtml"}})
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	issues := Validate(&r.Reader)
+	wantLoc := `content.opf#manifest/item[@id="missing"]`
+	if !hasIssue(issues, SeverityError, wantLoc) {
+		t.Errorf("issues = %+v, want an error at %q", issues, wantLoc)
+	}
+}
+
+func TestValidate_BadSpineIDRef(t *testing.T) {
+	opf := `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Broken Book</dc:title>
+    <dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="content" href="content.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine>
+    <itemref idref="nonexistent"/>
+  </spine>
+</package>
+`
+	path := writeTestEPUB(t, testBook{opf: opf, manifest: []string{"content.xhtml"}})
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	issues := Validate(&r.Reader)
+	wantLoc := "content.opf#spine/itemref[0]"
+	if !hasIssue(issues, SeverityError, wantLoc) {
+		t.Errorf("issues = %+v, want an error at %q", issues, wantLoc)
+	}
+}
+
+func TestValidate_MismatchedUniqueIdentifier(t *testing.T) {
+	opf := `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="does-not-exist">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Broken Book</dc:title>
+    <dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="content" href="content.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine>
+    <itemref idref="content"/>
+  </spine>
+</package>
+`
+	path := writeTestEPUB(t, testBook{opf: opf, manifest: []string{"content.xhtml"}})
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	issues := Validate(&r.Reader)
+	wantLoc := "content.opf#package[@unique-identifier]"
+	if !hasIssue(issues, SeverityError, wantLoc) {
+		t.Errorf("issues = %+v, want an error at %q", issues, wantLoc)
+	}
+}
+
+func TestValidate_MimetypeNotFirstOrStored(t *testing.T) {
+	path := writeTestEPUBWithBadMimetype(t)
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	issues := Validate(&r.Reader)
+	if !hasIssue(issues, SeverityError, "mimetype") {
+		t.Errorf("issues = %+v, want a mimetype error", issues)
+	}
+}
@@ -0,0 +1,150 @@
+package epub
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Result is one book's metadata, as produced by WalkDir.
+type Result struct {
+	// Path is the file that was scanned.
+	Path string
+	// Metadata is the book's metadata, valid only if Err is nil.
+	Metadata Metadata
+	// Err is any error encountered opening or parsing Path.
+	Err error
+	// Extra is the value returned by WalkOptions.Process for this book,
+	// or nil if no Process func was given or Err is set.
+	Extra any
+	// ProcessErr is the error returned by WalkOptions.Process, if any.
+	ProcessErr error
+	// Index is this result's 1-based position in completion order, and
+	// Total the number of files found under root; together they let a
+	// caller report "done/total" progress without tracking its own count.
+	Index, Total int
+}
+
+// WalkOptions configures WalkDir.
+type WalkOptions struct {
+	// Workers is the number of books parsed concurrently. Zero means
+	// runtime.NumCPU().
+	Workers int
+	// Process, if set, runs inside the worker goroutine for each book
+	// while its Reader is still open, before the file is closed and the
+	// Result sent on the channel. Use it for per-book work (e.g. cover or
+	// plain-text extraction) that would otherwise require reopening and
+	// reparsing the file on the caller's side. Its return value surfaces
+	// as Result.Extra.
+	Process func(path string, r *Reader) (any, error)
+}
+
+// WalkDir finds every .epub file under root and parses its metadata using
+// a bounded pool of worker goroutines, returning results on the returned
+// channel as they complete (not in any particular order). The channel is
+// closed once every file has been processed or ctx is canceled.
+func WalkDir(ctx context.Context, root string, opts WalkOptions) (<-chan Result, error) {
+	files, err := findEpubFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(files) {
+		workers = len(files)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	paths := make(chan string)
+	results := make(chan Result)
+	var done int32
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				md, extra, processErr, err := processFile(path, opts.Process)
+				n := int(atomic.AddInt32(&done, 1))
+				select {
+				case results <- Result{Path: path, Metadata: md, Err: err, Extra: extra, ProcessErr: processErr, Index: n, Total: len(files)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(paths)
+		for _, f := range files {
+			select {
+			case paths <- f:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results, nil
+}
+
+// findEpubFiles walks root looking for files with a .epub extension
+// (case-insensitive).
+func findEpubFiles(root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &os.PathError{Op: "walkdir", Path: root, Err: os.ErrInvalid}
+	}
+
+	var files []string
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".epub") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// processFile opens path, runs process against it (if non-nil) while it's
+// still open, and returns its metadata, closing the file before returning.
+// process's return value and error are passed through as extra and
+// processErr; they're independent of err, which reports failure to open or
+// parse path itself.
+func processFile(path string, process func(string, *Reader) (any, error)) (md Metadata, extra any, processErr, err error) {
+	r, err := Open(path)
+	if err != nil {
+		return Metadata{}, nil, nil, err
+	}
+	defer r.Close()
+
+	if process != nil {
+		extra, processErr = process(path, &r.Reader)
+	}
+	return r.Metadata, extra, processErr, nil
+}
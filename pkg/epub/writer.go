@@ -0,0 +1,264 @@
+package epub
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+)
+
+// Writer edits an existing EPUB's metadata and cover, then writes a new
+// EPUB preserving the invariants reading systems such as Calibre enforce:
+// mimetype stays the first entry, stored uncompressed; every other entry
+// is written deflated; META-INF/container.xml is carried over unchanged;
+// and the OPF is re-serialized with a stable element and attribute order
+// so diffs stay minimal.
+type Writer struct {
+	*Reader
+	f *os.File
+
+	newCoverData      []byte
+	newCoverMediaType string
+}
+
+// OpenWriter opens the EPUB file named by path for editing. The caller
+// must call Close when done.
+func OpenWriter(path string) (*Writer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r, err := NewReader(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Writer{Reader: r, f: f}, nil
+}
+
+// Close closes the underlying EPUB file.
+func (w *Writer) Close() error {
+	return w.f.Close()
+}
+
+// SetTitle replaces the book's title.
+func (w *Writer) SetTitle(title string) {
+	if len(w.Package.Metadata.Title) == 0 {
+		w.Package.Metadata.Title = []titleElem{{Value: title}}
+		return
+	}
+	w.Package.Metadata.Title[0].Value = title
+}
+
+// SetAuthors replaces the book's authors (dc:creator elements) with names,
+// each given the "aut" MARC relator role.
+func (w *Writer) SetAuthors(names []string) {
+	oldIDs := make(map[string]bool)
+	for _, c := range w.Package.Metadata.Creator {
+		if c.ID != "" {
+			oldIDs[c.ID] = true
+		}
+	}
+
+	creators := make([]creatorElem, len(names))
+	for i, name := range names {
+		creators[i] = creatorElem{Value: name, Role: "aut"}
+	}
+	w.Package.Metadata.Creator = creators
+	w.Package.Metadata.dropRefinesFor(oldIDs)
+}
+
+// SetIdentifiers replaces the book's identifiers. The first Identifier
+// with IsUnique set (or the first identifier overall, if none is marked)
+// becomes the package's unique-identifier.
+func (w *Writer) SetIdentifiers(ids []Identifier) {
+	oldIDs := make(map[string]bool)
+	for _, i := range w.Package.Metadata.Identifier {
+		if i.ID != "" {
+			oldIDs[i.ID] = true
+		}
+	}
+
+	elems := make([]identifierElem, len(ids))
+	uniqueIdx := -1
+	for i, id := range ids {
+		elems[i] = identifierElem{Value: id.Value, Scheme: id.Scheme}
+		if id.IsUnique && uniqueIdx == -1 {
+			uniqueIdx = i
+		}
+	}
+	if uniqueIdx == -1 && len(elems) > 0 {
+		uniqueIdx = 0
+	}
+	if uniqueIdx != -1 {
+		elems[uniqueIdx].ID = "uid"
+		w.Package.UniqueIdentifier = "uid"
+	} else {
+		w.Package.UniqueIdentifier = ""
+	}
+
+	w.Package.Metadata.Identifier = elems
+	w.Package.Metadata.dropRefinesFor(oldIDs)
+}
+
+// SetSubjects replaces the book's subjects (dc:subject elements).
+func (w *Writer) SetSubjects(subjects []string) {
+	w.Package.Metadata.Subject = append([]string{}, subjects...)
+}
+
+// SetCover replaces (or adds) the book's cover image. data and mediaType
+// are written out verbatim by WriteTo.
+func (w *Writer) SetCover(data []byte, mediaType string) {
+	w.newCoverData = data
+	w.newCoverMediaType = mediaType
+}
+
+// coverTarget resolves which manifest item the pending cover edit (if any)
+// should be written to, adding a new manifest item when the book declares
+// no cover yet. It returns the zip path the cover bytes belong at, or ""
+// if SetCover was never called.
+func (w *Writer) coverTarget() string {
+	if w.newCoverData == nil {
+		return ""
+	}
+
+	if item := w.Reader.coverItem(); item != nil {
+		item.MediaType = w.newCoverMediaType
+		return w.resolveHref(item.Href)
+	}
+
+	ext := ".img"
+	if exts, err := mime.ExtensionsByType(w.newCoverMediaType); err == nil && len(exts) > 0 {
+		ext = exts[0]
+	}
+
+	id := w.freeManifestID("cover-image")
+	href := w.freeManifestHref("cover" + ext)
+
+	w.Package.Manifest = append(w.Package.Manifest, manifestItem{
+		ID:         id,
+		Href:       href,
+		MediaType:  w.newCoverMediaType,
+		Properties: "cover-image",
+	})
+	w.Package.Metadata.Meta = append(w.Package.Metadata.Meta, metaElem{Name: "cover", Content: id})
+	return w.resolveHref(href)
+}
+
+// freeManifestID returns want, or want suffixed with an increasing number,
+// whichever doesn't collide with an existing manifest item id.
+func (w *Writer) freeManifestID(want string) string {
+	taken := make(map[string]bool, len(w.Package.Manifest))
+	for _, item := range w.Package.Manifest {
+		taken[item.ID] = true
+	}
+	id := want
+	for n := 2; taken[id]; n++ {
+		id = fmt.Sprintf("%s-%d", want, n)
+	}
+	return id
+}
+
+// freeManifestHref returns want, or want suffixed with an increasing
+// number (before the extension), whichever doesn't collide with an
+// existing manifest item href or zip entry.
+func (w *Writer) freeManifestHref(want string) string {
+	taken := make(map[string]bool, len(w.Package.Manifest))
+	for _, item := range w.Package.Manifest {
+		taken[w.resolveHref(item.Href)] = true
+	}
+	ext := path.Ext(want)
+	base := strings.TrimSuffix(want, ext)
+	href := want
+	for n := 2; taken[w.resolveHref(href)] || w.hrefFile(href) != nil; n++ {
+		href = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+	return href
+}
+
+// WriteTo writes a new EPUB reflecting the pending edits to dst.
+func (w *Writer) WriteTo(dst io.Writer) (int64, error) {
+	cw := &countingWriter{w: dst}
+	zw := zip.NewWriter(cw)
+
+	mimeWriter, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return cw.n, err
+	}
+	if _, err := mimeWriter.Write([]byte("application/epub+zip")); err != nil {
+		return cw.n, err
+	}
+
+	coverHref := w.coverTarget()
+	opfBytes := w.Package.render()
+
+	seen := make(map[string]bool, len(w.zr.File))
+	for _, f := range w.zr.File {
+		if f.Name == "mimetype" {
+			continue
+		}
+		seen[f.Name] = true
+
+		var content []byte
+		switch f.Name {
+		case w.opfPath:
+			content = opfBytes
+		case coverHref:
+			content = w.newCoverData
+		default:
+			rc, err := f.Open()
+			if err != nil {
+				return cw.n, err
+			}
+			content, err = io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return cw.n, err
+			}
+		}
+
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate})
+		if err != nil {
+			return cw.n, err
+		}
+		if _, err := fw.Write(content); err != nil {
+			return cw.n, err
+		}
+	}
+
+	// A cover that didn't exist before SetCover was called has no
+	// corresponding original zip entry; add it now.
+	if coverHref != "" && !seen[coverHref] {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: coverHref, Method: zip.Deflate})
+		if err != nil {
+			return cw.n, err
+		}
+		if _, err := fw.Write(w.newCoverData); err != nil {
+			return cw.n, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,333 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testBook describes the handful of OPF details writer_test.go cases vary;
+// everything else about the fixture EPUB is fixed.
+type testBook struct {
+	opf      string
+	manifest []string // extra zip entries beyond mimetype/container.xml/content.opf
+}
+
+// writeTestEPUB builds a minimal but structurally valid EPUB at a temp path
+// and returns it, so OpenWriter has a real file to edit.
+func writeTestEPUB(t *testing.T, b testBook) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "book.epub")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create fixture: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		t.Fatalf("write mimetype: %v", err)
+	}
+	mw.Write([]byte("application/epub+zip"))
+
+	cw, err := zw.Create("META-INF/container.xml")
+	if err != nil {
+		t.Fatalf("write container.xml: %v", err)
+	}
+	cw.Write([]byte(`<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`))
+
+	ow, err := zw.Create("content.opf")
+	if err != nil {
+		t.Fatalf("write content.opf: %v", err)
+	}
+	ow.Write([]byte(b.opf))
+
+	for _, name := range b.manifest {
+		ew, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		ew.Write([]byte("fake-" + name))
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close fixture zip: %v", err)
+	}
+	return path
+}
+
+// minimalOPF is a bare package document with a title, one creator (with an
+// EPUB 3 refines for file-as) and one identifier, and no cover.
+const minimalOPF = `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:title>Original Title</dc:title>
+    <dc:creator id="cre1">Original Author</dc:creator>
+    <meta refines="#cre1" property="file-as">Author, Original</meta>
+    <dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="content" href="content.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine>
+    <itemref idref="content"/>
+  </spine>
+</package>
+`
+
+// opfWithCover is like minimalOPF but already declares a cover image.
+const opfWithCover = `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:title>Original Title</dc:title>
+    <dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="content" href="content.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover-image" href="cover.jpg" media-type="image/jpeg" properties="cover-image"/>
+  </manifest>
+  <spine>
+    <itemref idref="content"/>
+  </spine>
+</package>
+`
+
+// readBack reopens the EPUB written to buf.
+func readBack(t *testing.T, buf []byte) *Reader {
+	t.Helper()
+	r, err := NewReader(bytes.NewReader(buf), int64(len(buf)))
+	if err != nil {
+		t.Fatalf("reopen written epub: %v", err)
+	}
+	return r
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	path := writeTestEPUB(t, testBook{opf: minimalOPF, manifest: []string{"content.xhtml"}})
+
+	w, err := OpenWriter(path)
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	defer w.Close()
+
+	w.SetTitle("New Title")
+	w.SetAuthors([]string{"New Author"})
+	w.SetIdentifiers([]Identifier{{Value: "urn:uuid:5678", IsUnique: true}})
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	r := readBack(t, buf.Bytes())
+
+	if r.Metadata.Title != "New Title" {
+		t.Errorf("Title = %q, want %q", r.Metadata.Title, "New Title")
+	}
+	if len(r.Metadata.Creators) != 1 || r.Metadata.Creators[0].Name != "New Author" {
+		t.Errorf("Creators = %+v, want [New Author]", r.Metadata.Creators)
+	}
+	if r.Metadata.Creators[0].FileAs != "" {
+		t.Errorf("Creators[0].FileAs = %q, want empty (stale refines for old creator should be dropped)", r.Metadata.Creators[0].FileAs)
+	}
+	if len(r.Metadata.Identifiers) != 1 || r.Metadata.Identifiers[0].Value != "urn:uuid:5678" || !r.Metadata.Identifiers[0].IsUnique {
+		t.Errorf("Identifiers = %+v, want one unique urn:uuid:5678", r.Metadata.Identifiers)
+	}
+
+	// mimetype must be the first entry, stored uncompressed, exact content.
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reopen as raw zip: %v", err)
+	}
+	if len(zr.File) == 0 || zr.File[0].Name != "mimetype" {
+		t.Fatalf("first entry = %v, want mimetype", zr.File[0].Name)
+	}
+	if zr.File[0].Method != zip.Store {
+		t.Errorf("mimetype Method = %v, want Store", zr.File[0].Method)
+	}
+	rc, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatalf("open mimetype entry: %v", err)
+	}
+	defer rc.Close()
+	var mimeBuf bytes.Buffer
+	mimeBuf.ReadFrom(rc)
+	if mimeBuf.String() != "application/epub+zip" {
+		t.Errorf("mimetype content = %q, want %q", mimeBuf.String(), "application/epub+zip")
+	}
+}
+
+func TestWriter_AddCoverToBookWithNone(t *testing.T) {
+	path := writeTestEPUB(t, testBook{opf: minimalOPF, manifest: []string{"content.xhtml"}})
+
+	w, err := OpenWriter(path)
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	defer w.Close()
+
+	coverData := []byte("fake-jpeg-bytes")
+	w.SetCover(coverData, "image/jpeg")
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	r := readBack(t, buf.Bytes())
+	data, mediaType, err := r.Cover()
+	if err != nil {
+		t.Fatalf("Cover: %v", err)
+	}
+	if !bytes.Equal(data, coverData) {
+		t.Errorf("Cover data = %q, want %q", data, coverData)
+	}
+	if mediaType != "image/jpeg" {
+		t.Errorf("Cover mediaType = %q, want image/jpeg", mediaType)
+	}
+
+	var coverItems int
+	for _, item := range r.Package.Manifest {
+		if item.Properties == "cover-image" {
+			coverItems++
+		}
+	}
+	if coverItems != 1 {
+		t.Errorf("found %d manifest items with properties=cover-image, want 1", coverItems)
+	}
+}
+
+func TestWriter_SetCoverCollidesWithExistingManifestID(t *testing.T) {
+	// This book already has a manifest item with id "cover-image" and href
+	// "cover.jpg" that isn't declared as the cover; SetCover must not
+	// silently overwrite either property on an unrelated item. Since
+	// opfWithCover's "cover-image" item IS already the cover, coverTarget
+	// should reuse it rather than minting a second one.
+	path := writeTestEPUB(t, testBook{opf: opfWithCover, manifest: []string{"content.xhtml", "cover.jpg"}})
+
+	w, err := OpenWriter(path)
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	defer w.Close()
+
+	w.SetCover([]byte("new-cover-bytes"), "image/png")
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	r := readBack(t, buf.Bytes())
+	if len(r.Package.Manifest) != 2 {
+		t.Fatalf("Manifest = %+v, want 2 items (no new item minted)", r.Package.Manifest)
+	}
+	data, mediaType, err := r.Cover()
+	if err != nil {
+		t.Fatalf("Cover: %v", err)
+	}
+	if string(data) != "new-cover-bytes" {
+		t.Errorf("Cover data = %q, want %q", data, "new-cover-bytes")
+	}
+	if mediaType != "image/png" {
+		t.Errorf("Cover mediaType = %q, want image/png", mediaType)
+	}
+}
+
+func TestWriter_FreeManifestIDAndHrefAvoidCollisions(t *testing.T) {
+	// A book that already has a (non-cover) manifest item occupying both the
+	// id "cover-image" and the href "cover.jpg" that coverTarget would
+	// otherwise pick for a brand-new cover.
+	opf := `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:title>Original Title</dc:title>
+    <dc:identifier id="uid">urn:uuid:1234</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="content" href="content.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover-image" href="cover.jpg" media-type="image/jpeg"/>
+  </manifest>
+  <spine>
+    <itemref idref="content"/>
+  </spine>
+</package>
+`
+	path := writeTestEPUB(t, testBook{opf: opf, manifest: []string{"content.xhtml", "cover.jpg"}})
+
+	w, err := OpenWriter(path)
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	defer w.Close()
+
+	w.SetCover([]byte("jpeg-bytes"), "image/jpeg")
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	r := readBack(t, buf.Bytes())
+	if len(r.Package.Manifest) != 3 {
+		t.Fatalf("Manifest = %+v, want 3 items (original cover-image item kept, new one added)", r.Package.Manifest)
+	}
+
+	var newItem *manifestItem
+	for i := range r.Package.Manifest {
+		if r.Package.Manifest[i].Properties == "cover-image" {
+			newItem = &r.Package.Manifest[i]
+		}
+	}
+	if newItem == nil {
+		t.Fatalf("no manifest item declares properties=cover-image: %+v", r.Package.Manifest)
+	}
+	if newItem.ID == "cover-image" {
+		t.Errorf("new cover item ID collides with existing item: %q", newItem.ID)
+	}
+	if newItem.Href == "cover.jpg" {
+		t.Errorf("new cover item Href collides with existing item: %q", newItem.Href)
+	}
+
+	data, _, err := r.Cover()
+	if err != nil {
+		t.Fatalf("Cover: %v", err)
+	}
+	if string(data) != "jpeg-bytes" {
+		t.Errorf("Cover data = %q, want %q", data, "jpeg-bytes")
+	}
+}
+
+func TestWriter_SetIdentifiersEmptyClearsUniqueIdentifier(t *testing.T) {
+	path := writeTestEPUB(t, testBook{opf: minimalOPF, manifest: []string{"content.xhtml"}})
+
+	w, err := OpenWriter(path)
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	defer w.Close()
+
+	w.SetIdentifiers(nil)
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	r := readBack(t, buf.Bytes())
+	if r.Package.UniqueIdentifier != "" {
+		t.Errorf("UniqueIdentifier = %q, want empty after clearing all identifiers", r.Package.UniqueIdentifier)
+	}
+	if len(r.Metadata.Identifiers) != 0 {
+		t.Errorf("Identifiers = %+v, want none", r.Metadata.Identifiers)
+	}
+}
@@ -0,0 +1,60 @@
+package epub
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// xml11To10Reader wraps an io.ReadCloser and converts XML 1.1 declarations to
+// XML 1.0. encoding/xml does not support XML 1.1, which is common in EPUB
+// metadata. See https://github.com/golang/go/issues/25755
+func xml11To10Reader(r io.ReadCloser) (io.Reader, error) {
+	// Read all content from reader
+	content, err := io.ReadAll(r)
+	if err != nil {
+		// If there's an error reading, return a reader that will return the error
+		return nil, err
+	}
+	r.Close()
+
+	// Check for XML declaration
+	if len(content) > 5 && bytes.HasPrefix(content, []byte("<?xml")) {
+		// Look for the XML declaration end
+		endPos := bytes.Index(content, []byte("?>"))
+		if endPos > 0 {
+			// Extract just the declaration part
+			declaration := content[:endPos+2]
+
+			// Simple replacements for common version patterns
+			replacements := []struct {
+				old string
+				new string
+			}{
+				{`version="1.1"`, `version="1.0"`},
+				{`version='1.1'`, `version='1.0'`},
+				{`version = "1.1"`, `version = "1.0"`},
+				{`version = '1.1'`, `version = '1.0'`},
+				{`version= "1.1"`, `version= "1.0"`},
+				{`version= '1.1'`, `version= '1.0'`},
+				{`version ="1.1"`, `version ="1.0"`},
+				{`version ='1.1'`, `version ='1.0'`},
+			}
+
+			// Apply all possible replacements
+			declarationStr := string(declaration)
+			for _, r := range replacements {
+				declarationStr = strings.Replace(declarationStr, r.old, r.new, 1)
+			}
+
+			// Combine the modified declaration with the rest of the content
+			result := []byte(declarationStr)
+			result = append(result, content[endPos+2:]...)
+
+			return bytes.NewReader(result), nil
+		}
+	}
+
+	// If no XML declaration found or no replacement needed, return original content
+	return bytes.NewReader(content), nil
+}